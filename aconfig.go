@@ -1,18 +1,11 @@
 package aconfig
 
 import (
-	"encoding/json"
-	"flag"
 	"fmt"
-	"os"
-	"path/filepath"
 	"reflect"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
-
-	"github.com/BurntSushi/toml"
-	"gopkg.in/yaml.v2"
 )
 
 const defaultValueTag = "default"
@@ -20,6 +13,28 @@ const defaultValueTag = "default"
 type Loader struct {
 	config LoaderConfig
 	fields []*fieldData
+
+	// dst is the struct passed to Load, kept around so Watch can build
+	// fresh copies of it to diff against.
+	dst interface{}
+	// mu guards writes to reloadable fields made from Watch while the
+	// rest of the struct is potentially being read by the caller. Callers
+	// that read reloadable fields concurrently with Watch must take a
+	// read lock via RLock/RUnlock to observe a consistent value.
+	mu sync.RWMutex
+}
+
+// RLock acquires a read lock held by Watch while it swaps reloadable
+// fields into the struct passed to Load. Callers that read reloadable
+// fields from another goroutine while Watch is running should wrap that
+// read in RLock/RUnlock to avoid racing with the swap.
+func (l *Loader) RLock() {
+	l.mu.RLock()
+}
+
+// RUnlock releases a read lock acquired with RLock.
+func (l *Loader) RUnlock() {
+	l.mu.RUnlock()
 }
 
 type LoaderConfig struct {
@@ -32,6 +47,32 @@ type LoaderConfig struct {
 	FlagPrefix string
 
 	Files []string
+
+	// SliceMergeMode controls how a slice value found in more than one
+	// file is merged. Defaults to SliceMergeReplace.
+	SliceMergeMode SliceMergeMode
+	// FailOnMissingFile makes Load fail if any of Files doesn't exist,
+	// instead of silently skipping it. Defaults to false, so optional
+	// overlay files like config.local.yaml can sit alongside a
+	// required config.yaml.
+	FailOnMissingFile bool
+
+	// SecretMask replaces `secret:"true"` fields in Loader.Dump.
+	// Defaults to "***".
+	SecretMask string
+
+	// WatchInterval is how often Watch re-runs providers that aren't
+	// backed by a watchable file, e.g. etcd/Consul/Vault. Defaults to
+	// 30 seconds.
+	WatchInterval time.Duration
+
+	// Providers, when non-empty, replaces the built-in Use*/Files based
+	// setup entirely: fields are populated by running each Provider in
+	// order, so precedence is exactly the order given here. This is the
+	// extension point for sources like etcd, Consul or Vault - see
+	// EtcdProvider, ConsulProvider and VaultProvider for ready-made
+	// implementations.
+	Providers []Provider
 }
 
 // DefaultConfig ...
@@ -54,115 +95,65 @@ func NewLoader(config LoaderConfig) *Loader {
 	return &Loader{config: config}
 }
 
+// Provider is implemented by types that can populate config fields from a
+// particular source: built-in defaults, files, environment variables and
+// flags, or an external store such as etcd, Consul or Vault. Register
+// custom providers via LoaderConfig.Providers, in the order they should
+// be applied - later providers win over earlier ones for any field they
+// set.
+type Provider interface {
+	// Name identifies the provider in error messages, e.g. "env" or
+	// "etcd".
+	Name() string
+	// Load populates fields with values read from the underlying
+	// source. dst is the struct passed to Loader.Load, for providers
+	// that decode into it directly rather than field by field; the
+	// built-in providers all work field by field and ignore it. A field
+	// with no value in this
+	// source should simply be left untouched.
+	Load(dst interface{}, fields []*fieldData) error
+}
+
 func (l *Loader) Load(into interface{}) error {
+	l.dst = into
 	l.fields = getFields(into)
 
-	if l.config.UseDefaults {
-		if err := l.loadDefaults(); err != nil {
-			return err
+	for _, p := range l.providers() {
+		if err := p.Load(into, l.fields); err != nil {
+			return fmt.Errorf("aconfig: %s provider: %s", p.Name(), err.Error())
 		}
 	}
-	if l.config.UseFile {
-		if err := l.loadFromFile(into); err != nil {
-			return err
-		}
-	}
-	if l.config.UseEnv {
-		if err := l.loadEnvironment(); err != nil {
-			return err
-		}
-	}
-	if l.config.UseFlag {
-		if err := l.loadFlags(); err != nil {
-			return err
-		}
+	if err := l.checkRequired(); err != nil {
+		return err
 	}
-	return nil
+	return l.validate(into)
 }
 
-func (l *Loader) loadDefaults() error {
-	for _, fd := range l.fields {
-		if err := l.setFieldData(fd, fd.DefaultValue); err != nil {
-			return err
-		}
+// providers returns LoaderConfig.Providers if set, otherwise builds the
+// provider list from the legacy UseDefaults/UseFile/UseEnv/UseFlag flags.
+func (l *Loader) providers() []Provider {
+	if len(l.config.Providers) > 0 {
+		return l.config.Providers
 	}
-	return nil
-}
 
-func (l *Loader) loadFromFile(dst interface{}) error {
-	for _, file := range l.config.Files {
-		f, err := os.Open(file)
-		if err != nil {
-			return err
-		}
-		defer func() { _ = f.Close() }()
-
-		ext := strings.ToLower(filepath.Ext(file))
-		switch ext {
-		case ".yaml", ".yml":
-			err = yaml.NewDecoder(f).Decode(dst)
-		case ".json":
-			err = json.NewDecoder(f).Decode(dst)
-		case ".toml":
-			_, err = toml.DecodeReader(f, dst)
-		default:
-			return fmt.Errorf("aconfig: file format '%q' isn't supported", ext)
-		}
-		if err != nil {
-			return fmt.Errorf("aconfig: file parsing error: %s", err.Error())
-		}
-		break
-	}
-	return nil
-}
-
-func (l *Loader) loadEnvironment() error {
-	for _, field := range l.fields {
-		envName := l.getEnvName(field.FullName())
-		v, ok := os.LookupEnv(envName)
-		if !ok {
-			continue
-		}
-		if err := l.setFieldData(field, v); err != nil {
-			return err
-		}
+	var providers []Provider
+	if l.config.UseDefaults {
+		providers = append(providers, &defaultsProvider{})
 	}
-	return nil
-}
-
-func (l *Loader) loadFlags() error {
-	if !flag.Parsed() {
-		flag.Parse()
+	if l.config.UseFile {
+		providers = append(providers, &fileProvider{
+			files:             l.config.Files,
+			sliceMergeMode:    l.config.SliceMergeMode,
+			failOnMissingFile: l.config.FailOnMissingFile,
+		})
 	}
-
-	for _, field := range l.fields {
-		flagName := l.getFlagName(field.FullName())
-		flg := flag.Lookup(flagName)
-		if flg == nil {
-			continue
-		}
-		if err := l.setFieldData(field, flg.Value.String()); err != nil {
-			return err
-		}
+	if l.config.UseEnv {
+		providers = append(providers, &envProvider{prefix: l.config.EnvPrefix})
 	}
-	return nil
-}
-
-func (l *Loader) getEnvName(name string) string {
-	return strings.ToUpper(l.config.EnvPrefix + strings.ReplaceAll(name, ".", "_"))
-}
-
-func (l *Loader) getFlagName(name string) string {
-	return strings.ToLower(l.config.FlagPrefix + name)
-}
-
-func (l *Loader) setFieldData(field *fieldData, value string) error {
-	setter, ok := settersByKind[field.Value.Kind()]
-	if ok {
-		return setter(field, value)
+	if l.config.UseFlag {
+		providers = append(providers, &flagProvider{prefix: l.config.FlagPrefix})
 	}
-	panic(fmt.Sprintf("unknown kind: %#v %#v", field.Value.Kind(), field))
-	return nil
+	return providers
 }
 
 func getFields(x interface{}) []*fieldData {
@@ -184,26 +175,43 @@ func getFieldsHelper(valueObject reflect.Value, parent *fieldData) []*fieldData
 			continue
 		}
 
-		// TODO: pointers
-
 		fd := &fieldData{
 			Name:         field.Name,
 			Parent:       parent,
 			Value:        value,
 			Field:        field,
 			DefaultValue: field.Tag.Get(defaultValueTag),
+			EnvNames:     splitTag(field.Tag.Get(envTag)),
+			FlagName:     field.Tag.Get(flagTag),
+			Description:  field.Tag.Get(usageTag),
+			Required:     field.Tag.Get(requiredTag) == "true",
+			Reloadable:   field.Tag.Get(reloadableTag) == "true",
+			Secret:       field.Tag.Get(secretTag) == "true",
+		}
+
+		underlying := field.Type
+		if underlying.Kind() == reflect.Ptr {
+			underlying = underlying.Elem()
 		}
 
 		// if just a field - add and process next, else expand struct
-		if field.Type.Kind() != reflect.Struct {
+		if underlying.Kind() != reflect.Struct || isLeafStruct(underlying) {
 			fields = append(fields, fd)
 		} else {
+			structValue := value
+			if field.Type.Kind() == reflect.Ptr {
+				if structValue.IsNil() {
+					structValue.Set(reflect.New(underlying))
+				}
+				structValue = structValue.Elem()
+			}
+
 			parent := fd
 			// remove prefix fpr embedded struct
 			if field.Anonymous {
 				parent = fd.Parent
 			}
-			fields = append(fields, getFieldsHelper(value, parent)...)
+			fields = append(fields, getFieldsHelper(structValue, parent)...)
 		}
 	}
 	return fields
@@ -215,6 +223,32 @@ type fieldData struct {
 	Field        reflect.StructField
 	Value        reflect.Value
 	DefaultValue string
+
+	// EnvNames are explicit `env:"..."` alternates, tried in order
+	// instead of the derived NAME. Empty unless the tag is present.
+	EnvNames []string
+	// FlagName is an explicit `flag:"..."` name, used instead of the
+	// derived dotted name when non-empty.
+	FlagName string
+	// Description comes from the `usage:"..."` tag, printed by
+	// Loader.Usage.
+	Description string
+	// Required comes from `required:"true"`; Load fails if the field
+	// is still at its zero value once every provider has run.
+	Required bool
+	// Reloadable comes from `reloadable:"true"`; only fields with this
+	// tag are mutated in place by Watch, under Loader.mu. Read such a
+	// field from another goroutine while Watch is running only inside
+	// Loader.RLock/RUnlock.
+	Reloadable bool
+	// Secret comes from `secret:"true"`; Dump replaces such fields
+	// with a mask instead of their real value.
+	Secret bool
+	// Source records which provider supplied the field's final value,
+	// e.g. "default", "file:config.yaml", "env:APP_DB_PASSWORD" or
+	// "flag:db.password". Set by the providers as they run; see
+	// Loader.Sources.
+	Source string
 }
 
 func (f *fieldData) FullName() string {
@@ -302,4 +336,4 @@ func setFloat(field *fieldData, value string) error {
 func setString(field *fieldData, value string) error {
 	field.Value.SetString(value)
 	return nil
-}
\ No newline at end of file
+}
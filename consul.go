@@ -0,0 +1,38 @@
+package aconfig
+
+import (
+	"strings"
+
+	capi "github.com/hashicorp/consul/api"
+)
+
+// ConsulProvider reads field values from Consul's KV store. Keys are
+// looked up as Prefix+FullName, e.g. with Prefix "myapp/" a field
+// Server.Port is read from the key "myapp/server.port".
+type ConsulProvider struct {
+	// Client is a connected Consul client, owned by the caller.
+	Client *capi.Client
+	// Prefix is prepended to each field's dotted name to form the key.
+	Prefix string
+}
+
+func (p *ConsulProvider) Name() string { return "consul" }
+
+func (p *ConsulProvider) Load(_ interface{}, fields []*fieldData) error {
+	kv := p.Client.KV()
+	for _, field := range fields {
+		key := p.Prefix + strings.ToLower(field.FullName())
+		pair, _, err := kv.Get(key, nil)
+		if err != nil {
+			return err
+		}
+		if pair == nil {
+			continue
+		}
+		if err := setFieldData(field, string(pair.Value)); err != nil {
+			return err
+		}
+		field.Source = "consul:" + key
+	}
+	return nil
+}
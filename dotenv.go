@@ -0,0 +1,83 @@
+package aconfig
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DotEnvProvider reads a file in the same KEY=VALUE format as the popular
+// godotenv package and applies it with the same name lookup as
+// envProvider, using Prefix in place of EnvPrefix. Unlike a real environment variable,
+// values loaded from the file never override variables that are already
+// set in the process environment, matching godotenv's default behaviour.
+type DotEnvProvider struct {
+	// Path is the .env file to read.
+	Path string
+	// Prefix is prepended as given to the field name before lookup;
+	// unlike LoaderConfig.EnvPrefix, no "_" separator is added to it.
+	Prefix string
+}
+
+func (p *DotEnvProvider) Name() string { return "dotenv" }
+
+func (p *DotEnvProvider) Load(_ interface{}, fields []*fieldData) error {
+	values, err := parseDotEnv(p.Path)
+	if err != nil {
+		return err
+	}
+
+	env := &envProvider{prefix: p.Prefix}
+	for _, field := range fields {
+		for _, name := range env.envNames(field) {
+			v, ok := values[name]
+			if !ok {
+				continue
+			}
+			if _, alreadySet := os.LookupEnv(name); alreadySet {
+				continue
+			}
+			if err := setFieldData(field, v); err != nil {
+				return err
+			}
+			field.Source = "dotenv:" + name
+			break
+		}
+	}
+	return nil
+}
+
+func parseDotEnv(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("aconfig: invalid dotenv line: %q", line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
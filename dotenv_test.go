@@ -0,0 +1,42 @@
+package aconfig
+
+import (
+	"os"
+	"testing"
+)
+
+type testDotEnvConfig struct {
+	Host string `env:"HOST"`
+}
+
+func TestDotEnvProviderDoesNotOverrideProcessEnv(t *testing.T) {
+	path := writeTempFile(t, "config.env", "HOST=fromfile\n")
+
+	t.Setenv("HOST", "fromenv")
+
+	var cfg testDotEnvConfig
+	provider := &DotEnvProvider{Path: path}
+	fields := getFields(&cfg)
+	if err := provider.Load(&cfg, fields); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Host != "" {
+		t.Fatalf("got Host=%q, want field left untouched since HOST is already set in the process environment", cfg.Host)
+	}
+}
+
+func TestDotEnvProviderAppliesWhenUnset(t *testing.T) {
+	path := writeTempFile(t, "config.env", "HOST=fromfile\n")
+
+	os.Unsetenv("HOST")
+
+	var cfg testDotEnvConfig
+	provider := &DotEnvProvider{Path: path}
+	fields := getFields(&cfg)
+	if err := provider.Load(&cfg, fields); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Host != "fromfile" {
+		t.Fatalf("got Host=%q, want %q", cfg.Host, "fromfile")
+	}
+}
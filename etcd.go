@@ -0,0 +1,48 @@
+package aconfig
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EtcdProvider reads field values from an etcd KV store. Keys are looked
+// up as Prefix+FullName, e.g. with Prefix "myapp/" a field Server.Port is
+// read from the key "myapp/server.port".
+type EtcdProvider struct {
+	// Client is a connected etcd client, owned by the caller.
+	Client *clientv3.Client
+	// Prefix is prepended to each field's dotted name to form the key.
+	Prefix string
+	// Timeout bounds each Get request. Defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+func (p *EtcdProvider) Name() string { return "etcd" }
+
+func (p *EtcdProvider) Load(_ interface{}, fields []*fieldData) error {
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	for _, field := range fields {
+		key := p.Prefix + strings.ToLower(field.FullName())
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		resp, err := p.Client.Get(ctx, key)
+		cancel()
+		if err != nil {
+			return err
+		}
+		if len(resp.Kvs) == 0 {
+			continue
+		}
+		if err := setFieldData(field, string(resp.Kvs[0].Value)); err != nil {
+			return err
+		}
+		field.Source = "etcd:" + key
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package aconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// SliceMergeMode controls how fileProvider merges a slice value found in
+// more than one file.
+type SliceMergeMode int
+
+const (
+	// SliceMergeReplace makes a later file's slice replace an earlier
+	// one entirely. This is the default.
+	SliceMergeReplace SliceMergeMode = iota
+	// SliceMergeAppend concatenates a later file's slice onto an
+	// earlier one instead of replacing it.
+	SliceMergeAppend
+)
+
+// decodeFileToMap decodes file into a generic map, based on its
+// extension: yaml, json, toml or dotenv (.env).
+func decodeFileToMap(file string) (map[string]interface{}, error) {
+	ext := strings.ToLower(filepath.Ext(file))
+
+	if ext == ".env" {
+		values, err := parseDotEnv(file)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]interface{}, len(values))
+		for k, v := range values {
+			m[k] = v
+		}
+		return m, nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	m := map[string]interface{}{}
+	switch ext {
+	case ".yaml", ".yml":
+		if err = yaml.NewDecoder(f).Decode(&m); err == nil {
+			// yaml.v2 decodes nested mappings as map[interface{}]interface{},
+			// which neither json.Marshal nor our own map lookups understand.
+			for k, v := range m {
+				m[k] = normalizeYAMLValue(v)
+			}
+		}
+	case ".json":
+		err = json.NewDecoder(f).Decode(&m)
+	case ".toml":
+		_, err = toml.DecodeReader(f, &m)
+	default:
+		return nil, fmt.Errorf("aconfig: file format '%q' isn't supported", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("aconfig: file parsing error: %s", err.Error())
+	}
+	return m, nil
+}
+
+// normalizeYAMLValue recursively rewrites map[interface{}]interface{}
+// values - how yaml.v2 represents any nested mapping - into
+// map[string]interface{}, descending into slices too. Scalars are
+// returned unchanged.
+func normalizeYAMLValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, vv := range val {
+			m[fmt.Sprintf("%v", k)] = normalizeYAMLValue(vv)
+		}
+		return m
+	case map[string]interface{}:
+		for k, vv := range val {
+			val[k] = normalizeYAMLValue(vv)
+		}
+		return val
+	case []interface{}:
+		for i, vv := range val {
+			val[i] = normalizeYAMLValue(vv)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// tagName returns the key that should identify field in a decoded config
+// file: its `yaml` tag if present, else its `json` tag, else its Go
+// field name. This is what lets `json:"db_password"` (or a `yaml` tag)
+// match a snake_case file key under any of yaml/json/toml.
+func tagName(field reflect.StructField) string {
+	for _, tag := range [...]string{"yaml", "json"} {
+		if v := field.Tag.Get(tag); v != "" {
+			name, _, _ := strings.Cut(v, ",")
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+	}
+	return field.Name
+}
+
+// filePath returns fd's lookup path into a decoded config file, using
+// tagName at every level (not just the leaf), so a `yaml`/`json` tag on
+// a nested struct field is honored too.
+func (fd *fieldData) filePath() []string {
+	if fd == nil {
+		return nil
+	}
+	return append(fd.Parent.filePath(), tagName(fd.Field))
+}
+
+// jsonName returns the key field will appear under in the output of
+// encoding/json, honoring a `json` struct tag the same way the standard
+// library does - unlike tagName, it never falls back to a `yaml` tag,
+// since that's not something json.Marshal looks at.
+func jsonName(field reflect.StructField) string {
+	if v := field.Tag.Get("json"); v != "" {
+		name, _, _ := strings.Cut(v, ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// jsonPath mirrors filePath but uses jsonName at every level, matching
+// the keys produced by json.Marshal(l.dst) in Loader.Dump so secret
+// fields can be found and masked regardless of their `json` tag.
+func (fd *fieldData) jsonPath() []string {
+	if fd == nil {
+		return nil
+	}
+	return append(fd.Parent.jsonPath(), jsonName(fd.Field))
+}
+
+// lookupPath looks up the value reachable from m by following path
+// level by level, matching map keys to path segments case-insensitively
+// (the same way encoding/json matches JSON keys to Go field names).
+func lookupPath(m map[string]interface{}, path []string) (interface{}, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+	for key, value := range m {
+		if !strings.EqualFold(key, path[0]) {
+			continue
+		}
+		if len(path) == 1 {
+			return value, true
+		}
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		return lookupPath(nested, path[1:])
+	}
+	return nil, false
+}
+
+// deepMergeMaps merges overlay into base in place and returns base:
+// scalars and maps in overlay override base's, nested maps are merged
+// recursively, and slices either replace or append depending on mode.
+func deepMergeMaps(base, overlay map[string]interface{}, mode SliceMergeMode) map[string]interface{} {
+	for key, overlayValue := range overlay {
+		baseValue, exists := base[key]
+		if !exists {
+			base[key] = overlayValue
+			continue
+		}
+
+		switch typedOverlay := overlayValue.(type) {
+		case map[string]interface{}:
+			if typedBase, ok := baseValue.(map[string]interface{}); ok {
+				base[key] = deepMergeMaps(typedBase, typedOverlay, mode)
+			} else {
+				base[key] = typedOverlay
+			}
+		case []interface{}:
+			if mode == SliceMergeAppend {
+				if typedBase, ok := baseValue.([]interface{}); ok {
+					base[key] = append(append([]interface{}{}, typedBase...), typedOverlay...)
+					continue
+				}
+			}
+			base[key] = typedOverlay
+		default:
+			base[key] = overlayValue
+		}
+	}
+	return base
+}
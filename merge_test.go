@@ -0,0 +1,138 @@
+package aconfig
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testServerConfig struct {
+	Server struct {
+		Host string
+		Port int
+	}
+}
+
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+	return path
+}
+
+func TestFileProviderNestedYAML(t *testing.T) {
+	path := writeTempFile(t, "config.yaml", "server:\n  host: localhost\n  port: 8080\n")
+
+	var cfg testServerConfig
+	loader := NewLoader(LoaderConfig{UseFile: true, Files: []string{path}})
+	if err := loader.Load(&cfg); err != nil {
+		t.Fatalf("Load returned error for nested YAML config: %v", err)
+	}
+	if cfg.Server.Host != "localhost" || cfg.Server.Port != 8080 {
+		t.Fatalf("got %+v, want Host=localhost Port=8080", cfg.Server)
+	}
+}
+
+type testDBConfig struct {
+	DBPassword string `json:"db_password"`
+}
+
+func TestFileProviderHonorsJSONTagAcrossFormats(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		file string
+		data string
+	}{
+		{"json", "config.json", `{"db_password": "secret"}`},
+		{"yaml", "config.yaml", "db_password: secret\n"},
+		{"toml", "config.toml", `db_password = "secret"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeTempFile(t, tc.file, tc.data)
+
+			var cfg testDBConfig
+			loader := NewLoader(LoaderConfig{UseFile: true, Files: []string{path}})
+			if err := loader.Load(&cfg); err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.DBPassword != "secret" {
+				t.Fatalf("got DBPassword=%q, want %q", cfg.DBPassword, "secret")
+			}
+		})
+	}
+}
+
+type testYAMLTaggedConfig struct {
+	APIKey string `yaml:"api_key"`
+}
+
+// TestFileProviderHonorsYAMLOnlyTag guards against field values being
+// populated by keying the final struct write-back on the Go field name
+// (e.g. "APIKey") instead of the tag-aware path used to look the value
+// up in the decoded file (e.g. "api_key") - a field with only a `yaml`
+// tag, no `json` tag, has nothing else linking it to its file key.
+func TestFileProviderHonorsYAMLOnlyTag(t *testing.T) {
+	path := writeTempFile(t, "config.yaml", "api_key: secret\n")
+
+	var cfg testYAMLTaggedConfig
+	loader := NewLoader(LoaderConfig{UseFile: true, Files: []string{path}})
+	if err := loader.Load(&cfg); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.APIKey != "secret" {
+		t.Fatalf("got APIKey=%q, want %q", cfg.APIKey, "secret")
+	}
+}
+
+type testFileTypesConfig struct {
+	Endpoint url.URL  `yaml:"endpoint"`
+	Host     net.IP   `yaml:"host"`
+	Tags     []string `yaml:"tags"`
+}
+
+func TestFileProviderAppliesWellKnownTypesAndSlices(t *testing.T) {
+	path := writeTempFile(t, "config.yaml",
+		"endpoint: https://example.com/path\nhost: 127.0.0.1\ntags:\n  - a\n  - b\n")
+
+	var cfg testFileTypesConfig
+	loader := NewLoader(LoaderConfig{UseFile: true, Files: []string{path}})
+	if err := loader.Load(&cfg); err != nil {
+		t.Fatalf("Load returned error for url.URL/net.IP/slice file fields: %v", err)
+	}
+	if cfg.Endpoint.Host != "example.com" || cfg.Endpoint.Path != "/path" {
+		t.Fatalf("got Endpoint=%v, want host=example.com path=/path", cfg.Endpoint)
+	}
+	if cfg.Host.String() != "127.0.0.1" {
+		t.Fatalf("got Host=%v, want 127.0.0.1", cfg.Host)
+	}
+	if len(cfg.Tags) != 2 || cfg.Tags[0] != "a" || cfg.Tags[1] != "b" {
+		t.Fatalf("got Tags=%v, want [a b]", cfg.Tags)
+	}
+}
+
+func TestDeepMergeMapsSliceModes(t *testing.T) {
+	base := map[string]interface{}{"tags": []interface{}{"a", "b"}}
+	overlay := map[string]interface{}{"tags": []interface{}{"c"}}
+
+	replaced := deepMergeMaps(copyMap(base), overlay, SliceMergeReplace)
+	if got := replaced["tags"].([]interface{}); len(got) != 1 || got[0] != "c" {
+		t.Fatalf("SliceMergeReplace: got %v, want [c]", got)
+	}
+
+	appended := deepMergeMaps(copyMap(base), overlay, SliceMergeAppend)
+	if got := appended["tags"].([]interface{}); len(got) != 3 {
+		t.Fatalf("SliceMergeAppend: got %v, want [a b c]", got)
+	}
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,225 @@
+package aconfig
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// defaultsProvider applies the `default` struct tag to every field.
+type defaultsProvider struct{}
+
+func (p *defaultsProvider) Name() string { return "defaults" }
+
+func (p *defaultsProvider) Load(_ interface{}, fields []*fieldData) error {
+	for _, fd := range fields {
+		if err := setFieldData(fd, fd.DefaultValue); err != nil {
+			return err
+		}
+		fd.Source = "default"
+	}
+	return nil
+}
+
+// fileProvider decodes every file in files into an intermediate map,
+// deep-merging them in order (later files override earlier ones), then
+// applies the merged result to dst.
+type fileProvider struct {
+	files             []string
+	sliceMergeMode    SliceMergeMode
+	failOnMissingFile bool
+}
+
+func (p *fileProvider) Name() string { return "file" }
+
+func (p *fileProvider) Load(_ interface{}, fields []*fieldData) error {
+	merged := map[string]interface{}{}
+	var decoded []map[string]interface{}
+	var decodedFiles []string
+
+	for _, file := range p.files {
+		m, err := decodeFileToMap(file)
+		if err != nil {
+			if os.IsNotExist(err) {
+				if p.failOnMissingFile {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+		merged = deepMergeMaps(merged, m, p.sliceMergeMode)
+		decoded = append(decoded, m)
+		decodedFiles = append(decodedFiles, file)
+	}
+	if len(decoded) == 0 {
+		return nil
+	}
+
+	// Apply each field's value through the same setFieldData used by
+	// every other provider, found via its `yaml`/`json`-tag-aware path
+	// so a tagged field is matched regardless of which file format
+	// actually set it. This is what lets file-sourced values reach
+	// Setter/encoding.TextUnmarshaler and the well-known types from
+	// types.go, not just plain scalars.
+	for _, fd := range fields {
+		value, ok := lookupPath(merged, fd.filePath())
+		if !ok {
+			continue
+		}
+		if err := applyDecodedValue(fd, value); err != nil {
+			return err
+		}
+
+		for i := len(decoded) - 1; i >= 0; i-- {
+			if _, ok := lookupPath(decoded[i], fd.filePath()); ok {
+				fd.Source = "file:" + decodedFiles[i]
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// applyDecodedValue sets fd's value from value, a generic value decoded
+// from a config file (a string, bool, float64/int64, nested map or
+// list), by routing it through setFieldData - the same function env and
+// flag values go through - rather than a json.Marshal/Unmarshal round
+// trip. Lists and maps are joined back into the separator-delimited
+// strings setSliceField and setMapField already know how to parse.
+func applyDecodedValue(fd *fieldData, value interface{}) error {
+	switch fd.Field.Type {
+	case timeType, urlType, ipType, ipNetType:
+		return setFieldData(fd, fmt.Sprintf("%v", value))
+	}
+	if fd.Value.CanAddr() {
+		addr := fd.Value.Addr().Interface()
+		if _, ok := addr.(Setter); ok {
+			return setFieldData(fd, fmt.Sprintf("%v", value))
+		}
+		if _, ok := addr.(encoding.TextUnmarshaler); ok {
+			return setFieldData(fd, fmt.Sprintf("%v", value))
+		}
+	}
+
+	switch fd.Value.Kind() {
+	case reflect.Slice:
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("aconfig: field %q: expected a list, got %T", fd.FullName(), value)
+		}
+		sep := fd.Field.Tag.Get("separator")
+		if sep == "" {
+			sep = ","
+		}
+		parts := make([]string, len(list))
+		for i, v := range list {
+			parts[i] = fmt.Sprintf("%v", v)
+		}
+		return setFieldData(fd, strings.Join(parts, sep))
+	case reflect.Map:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("aconfig: field %q: expected a map, got %T", fd.FullName(), value)
+		}
+		sep := fd.Field.Tag.Get("separator")
+		if sep == "" {
+			sep = ","
+		}
+		mapSep := fd.Field.Tag.Get("map-separator")
+		if mapSep == "" {
+			mapSep = ":"
+		}
+		parts := make([]string, 0, len(m))
+		for k, v := range m {
+			parts = append(parts, fmt.Sprintf("%s%s%v", k, mapSep, v))
+		}
+		return setFieldData(fd, strings.Join(parts, sep))
+	default:
+		return setFieldData(fd, fmt.Sprintf("%v", value))
+	}
+}
+
+// envProvider reads field values from environment variables.
+type envProvider struct {
+	prefix string
+}
+
+func (p *envProvider) Name() string { return "env" }
+
+func (p *envProvider) Load(_ interface{}, fields []*fieldData) error {
+	for _, field := range fields {
+		for _, envName := range p.envNames(field) {
+			v, ok := os.LookupEnv(envName)
+			if !ok {
+				continue
+			}
+			if err := setFieldData(field, v); err != nil {
+				return err
+			}
+			field.Source = "env:" + envName
+			break
+		}
+	}
+	return nil
+}
+
+func (p *envProvider) envName(name string) string {
+	return strings.ToUpper(p.prefix + strings.ReplaceAll(name, ".", "_"))
+}
+
+// envNames returns the candidate environment variable names for field,
+// in lookup order: its `env:"..."` alternates if any, otherwise its
+// single name derived from FullName().
+func (p *envProvider) envNames(field *fieldData) []string {
+	if len(field.EnvNames) == 0 {
+		return []string{p.envName(field.FullName())}
+	}
+	names := make([]string, len(field.EnvNames))
+	for i, n := range field.EnvNames {
+		names[i] = p.envName(n)
+	}
+	return names
+}
+
+// flagProvider reads field values from registered command-line flags.
+type flagProvider struct {
+	prefix string
+}
+
+func (p *flagProvider) Name() string { return "flag" }
+
+func (p *flagProvider) Load(_ interface{}, fields []*fieldData) error {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+
+	for _, field := range fields {
+		flagName := p.flagNameFor(field)
+		flg := flag.Lookup(flagName)
+		if flg == nil {
+			continue
+		}
+		if err := setFieldData(field, flg.Value.String()); err != nil {
+			return err
+		}
+		field.Source = "flag:" + flagName
+	}
+	return nil
+}
+
+func (p *flagProvider) flagName(name string) string {
+	return strings.ToLower(p.prefix + name)
+}
+
+// flagNameFor returns field's explicit `flag:"..."` name if set,
+// otherwise its name derived from FullName().
+func (p *flagProvider) flagNameFor(field *fieldData) string {
+	if field.FlagName != "" {
+		return p.flagName(field.FlagName)
+	}
+	return p.flagName(field.FullName())
+}
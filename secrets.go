@@ -0,0 +1,89 @@
+package aconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+const defaultSecretMask = "***"
+
+// Sources returns, for every field's FullName(), which provider supplied
+// its final value: "default", "file:config.yaml", "env:APP_DB_PASSWORD",
+// "flag:db.password" and so on. A field no provider touched - e.g.
+// because UseDefaults is off and it was never set - is reported as
+// "unset". Call it after Load.
+func (l *Loader) Sources() map[string]string {
+	sources := make(map[string]string, len(l.fields))
+	for _, fd := range l.fields {
+		source := fd.Source
+		if source == "" {
+			source = "unset"
+		}
+		sources[fd.FullName()] = source
+	}
+	return sources
+}
+
+// Dump serializes the currently loaded config to the given format
+// ("json", "yaml" or "toml"), replacing every `secret:"true"` field with
+// LoaderConfig.SecretMask ("***" by default). Call it after Load.
+func (l *Loader) Dump(w io.Writer, format string) error {
+	data, err := json.Marshal(l.dst)
+	if err != nil {
+		return err
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	mask := l.config.SecretMask
+	if mask == "" {
+		mask = defaultSecretMask
+	}
+	for _, fd := range l.fields {
+		if fd.Secret {
+			maskPath(generic, fd.jsonPath(), mask)
+		}
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(generic)
+	case "yaml", "yml":
+		return yaml.NewEncoder(w).Encode(generic)
+	case "toml":
+		return toml.NewEncoder(w).Encode(generic)
+	default:
+		return fmt.Errorf("aconfig: dump format %q isn't supported", format)
+	}
+}
+
+// maskPath walks m by parts, matching keys case-insensitively the same
+// way lookupPath does, and replaces the value found at the end of the
+// path with mask.
+func maskPath(m map[string]interface{}, parts []string, mask string) {
+	if len(parts) == 0 {
+		return
+	}
+	for key, value := range m {
+		if !strings.EqualFold(key, parts[0]) {
+			continue
+		}
+		if len(parts) == 1 {
+			m[key] = mask
+			return
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			maskPath(nested, parts[1:], mask)
+		}
+		return
+	}
+}
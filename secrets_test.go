@@ -0,0 +1,31 @@
+package aconfig
+
+import (
+	"bytes"
+	"testing"
+)
+
+type testSecretConfig struct {
+	DBPassword string `json:"db_password" secret:"true"`
+	Host       string `json:"host"`
+}
+
+func TestDumpMasksSecretFieldWithMismatchedJSONTag(t *testing.T) {
+	cfg := testSecretConfig{DBPassword: "supersecret", Host: "localhost"}
+	loader := NewLoader(LoaderConfig{})
+	loader.dst = &cfg
+	loader.fields = getFields(&cfg)
+
+	var buf bytes.Buffer
+	if err := loader.Dump(&buf, "json"); err != nil {
+		t.Fatalf("Dump: %v", err)
+	}
+
+	out := buf.String()
+	if bytes.Contains(buf.Bytes(), []byte("supersecret")) {
+		t.Fatalf("Dump leaked the secret value in clear text: %s", out)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(defaultSecretMask)) {
+		t.Fatalf("Dump didn't mask db_password, got: %s", out)
+	}
+}
@@ -0,0 +1,66 @@
+package aconfig
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	envTag        = "env"
+	flagTag       = "flag"
+	requiredTag   = "required"
+	usageTag      = "usage"
+	reloadableTag = "reloadable"
+	secretTag     = "secret"
+)
+
+// splitTag splits a comma-separated tag value into its parts, trimming
+// surrounding whitespace from each one. An empty tag yields nil.
+func splitTag(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+	parts := strings.Split(tag, ",")
+	for i, p := range parts {
+		parts[i] = strings.TrimSpace(p)
+	}
+	return parts
+}
+
+// Usage writes a table describing every field of the struct passed to
+// Load: its dotted name, the environment variable(s) and flag name it is
+// read from, its default value and its `usage` description. Call it
+// after Load, e.g. to implement a `--help` flag.
+func (l *Loader) Usage(w io.Writer) {
+	env := &envProvider{prefix: l.config.EnvPrefix}
+	flg := &flagProvider{prefix: l.config.FlagPrefix}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "FIELD\tENV\tFLAG\tDEFAULT\tDESCRIPTION")
+	for _, fd := range l.fields {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			fd.FullName(),
+			strings.Join(env.envNames(fd), ","),
+			flg.flagNameFor(fd),
+			fd.DefaultValue,
+			fd.Description,
+		)
+	}
+	_ = tw.Flush()
+}
+
+// checkRequired fails Load if any field tagged `required:"true"` was
+// left at its zero value after every provider ran.
+func (l *Loader) checkRequired() error {
+	for _, fd := range l.fields {
+		if !fd.Required {
+			continue
+		}
+		if fd.Value.IsZero() {
+			return fmt.Errorf("aconfig: required field %q is not set", fd.FullName())
+		}
+	}
+	return nil
+}
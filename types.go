@@ -0,0 +1,225 @@
+package aconfig
+
+import (
+	"encoding"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Setter can be implemented by a field's type to take full control over
+// how a string value coming from any provider is parsed into it. It is
+// checked before aconfig's own scalar, slice, map and well-known-type
+// handling, so it also works for types aconfig has no built-in support
+// for. encoding.TextUnmarshaler is honoured the same way, for types that
+// already implement it for JSON/YAML. time.Time is the one exception: it
+// is always parsed with its `env-layout` tag (RFC3339 by default), not
+// through its own UnmarshalText.
+type Setter interface {
+	SetValue(value string) error
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	locationType = reflect.TypeOf(time.Location{})
+	urlType      = reflect.TypeOf(url.URL{})
+	ipType       = reflect.TypeOf(net.IP{})
+	ipNetType    = reflect.TypeOf(net.IPNet{})
+
+	setterType          = reflect.TypeOf((*Setter)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isLeafStruct reports whether t, a struct type, should be treated as a
+// single scalar field rather than expanded into its own fields - either
+// because aconfig has dedicated parsing for it, or because it implements
+// Setter/encoding.TextUnmarshaler itself.
+func isLeafStruct(t reflect.Type) bool {
+	switch t {
+	case timeType, locationType, urlType, ipNetType:
+		return true
+	}
+	ptr := reflect.PtrTo(t)
+	return ptr.Implements(setterType) || ptr.Implements(textUnmarshalerType)
+}
+
+func setFieldData(field *fieldData, value string) error {
+	// time.Time implements encoding.TextUnmarshaler, so it has to be
+	// handled first for its `env-layout` tag to take effect.
+	if field.Field.Type == timeType {
+		return setTime(field, value)
+	}
+	if field.Value.CanAddr() {
+		addr := field.Value.Addr().Interface()
+		if s, ok := addr.(Setter); ok {
+			return s.SetValue(value)
+		}
+		if u, ok := addr.(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(value))
+		}
+	}
+
+	switch field.Field.Type {
+	case urlType:
+		return setURL(field, value)
+	case ipType:
+		return setIP(field, value)
+	case ipNetType:
+		return setIPNet(field, value)
+	}
+
+	switch field.Value.Kind() {
+	case reflect.Ptr:
+		return setPointer(field, value)
+	case reflect.Slice:
+		return setSliceField(field, value)
+	case reflect.Map:
+		return setMapField(field, value)
+	}
+
+	setter, ok := settersByKind[field.Value.Kind()]
+	if ok {
+		return setter(field, value)
+	}
+	panic(fmt.Sprintf("unknown kind: %#v %#v", field.Value.Kind(), field))
+}
+
+func setTime(field *fieldData, value string) error {
+	layout := field.Field.Tag.Get("env-layout")
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return err
+	}
+	field.Value.Set(reflect.ValueOf(t))
+	return nil
+}
+
+func setURL(field *fieldData, value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	field.Value.Set(reflect.ValueOf(*u))
+	return nil
+}
+
+func setIP(field *fieldData, value string) error {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return fmt.Errorf("aconfig: invalid IP address: %q", value)
+	}
+	field.Value.Set(reflect.ValueOf(ip))
+	return nil
+}
+
+func setIPNet(field *fieldData, value string) error {
+	_, ipNet, err := net.ParseCIDR(value)
+	if err != nil {
+		return err
+	}
+	field.Value.Set(reflect.ValueOf(*ipNet))
+	return nil
+}
+
+// setPointer allocates the pointer if needed and sets the pointee,
+// special-casing *time.Location since time.LoadLocation is the only
+// sane way to build one.
+func setPointer(field *fieldData, value string) error {
+	if field.Value.IsNil() {
+		field.Value.Set(reflect.New(field.Value.Type().Elem()))
+	}
+
+	elemType := field.Value.Type().Elem()
+	if elemType == locationType {
+		loc, err := time.LoadLocation(value)
+		if err != nil {
+			return err
+		}
+		field.Value.Elem().Set(reflect.ValueOf(*loc))
+		return nil
+	}
+
+	return setFieldData(&fieldData{
+		Name:  field.Name,
+		Field: reflect.StructField{Type: elemType, Tag: field.Field.Tag},
+		Value: field.Value.Elem(),
+	}, value)
+}
+
+// setSliceField splits value on the `separator` tag (default ",") and
+// parses each part as the slice's element type.
+func setSliceField(field *fieldData, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	sep := field.Field.Tag.Get("separator")
+	if sep == "" {
+		sep = ","
+	}
+
+	parts := strings.Split(value, sep)
+	elemType := field.Value.Type().Elem()
+	slice := reflect.MakeSlice(field.Value.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		elem := &fieldData{
+			Name:  field.Name,
+			Field: reflect.StructField{Type: elemType, Tag: field.Field.Tag},
+			Value: slice.Index(i),
+		}
+		if err := setFieldData(elem, strings.TrimSpace(part)); err != nil {
+			return err
+		}
+	}
+	field.Value.Set(slice)
+	return nil
+}
+
+// setMapField splits value into "key:value" pairs separated by the
+// `separator` tag (default ",") with keys and values split by the
+// `map-separator` tag (default ":").
+func setMapField(field *fieldData, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	sep := field.Field.Tag.Get("separator")
+	if sep == "" {
+		sep = ","
+	}
+	mapSep := field.Field.Tag.Get("map-separator")
+	if mapSep == "" {
+		mapSep = ":"
+	}
+
+	mapType := field.Value.Type()
+	m := reflect.MakeMap(mapType)
+	for _, pair := range strings.Split(value, sep) {
+		k, v, ok := strings.Cut(pair, mapSep)
+		if !ok {
+			return fmt.Errorf("aconfig: invalid map entry %q, expected key%svalue", pair, mapSep)
+		}
+
+		keyValue := reflect.New(mapType.Key()).Elem()
+		keyField := &fieldData{Name: field.Name, Field: reflect.StructField{Type: mapType.Key()}, Value: keyValue}
+		if err := setFieldData(keyField, strings.TrimSpace(k)); err != nil {
+			return err
+		}
+
+		elemValue := reflect.New(mapType.Elem()).Elem()
+		elemField := &fieldData{Name: field.Name, Field: reflect.StructField{Type: mapType.Elem(), Tag: field.Field.Tag}, Value: elemValue}
+		if err := setFieldData(elemField, strings.TrimSpace(v)); err != nil {
+			return err
+		}
+
+		m.SetMapIndex(keyValue, elemValue)
+	}
+	field.Value.Set(m)
+	return nil
+}
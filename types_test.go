@@ -0,0 +1,117 @@
+package aconfig
+
+import (
+	"net"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type testTypesConfig struct {
+	Tags   []string       `env:"TAGS"`
+	Ports  []int          `env:"PORTS" separator:"|"`
+	Labels map[string]int `env:"LABELS" map-separator:"="`
+
+	StartedAt time.Time     `env:"STARTED_AT"`
+	Timeout   time.Duration `env:"TIMEOUT"`
+
+	Endpoint url.URL   `env:"ENDPOINT"`
+	Host     net.IP    `env:"HOST"`
+	Network  net.IPNet `env:"NETWORK"`
+}
+
+func loadFromEnv(t *testing.T, cfg interface{}, env map[string]string) error {
+	t.Helper()
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	loader := NewLoader(LoaderConfig{UseEnv: true})
+	return loader.Load(cfg)
+}
+
+func TestSetFieldDataSliceAndMap(t *testing.T) {
+	var cfg testTypesConfig
+	err := loadFromEnv(t, &cfg, map[string]string{
+		"TAGS":   "a,b,c",
+		"PORTS":  "80|443",
+		"LABELS": "a=1,b=2",
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.Tags; len(got) != 3 || got[0] != "a" || got[2] != "c" {
+		t.Fatalf("Tags = %v, want [a b c]", got)
+	}
+	if got := cfg.Ports; len(got) != 2 || got[0] != 80 || got[1] != 443 {
+		t.Fatalf("Ports = %v, want [80 443]", got)
+	}
+	if got := cfg.Labels; got["a"] != 1 || got["b"] != 2 {
+		t.Fatalf("Labels = %v, want map[a:1 b:2]", got)
+	}
+}
+
+func TestSetFieldDataWellKnownTypes(t *testing.T) {
+	var cfg testTypesConfig
+	err := loadFromEnv(t, &cfg, map[string]string{
+		"STARTED_AT": "2024-01-02T15:04:05Z",
+		"TIMEOUT":    "30s",
+		"ENDPOINT":   "https://example.com/path",
+		"HOST":       "127.0.0.1",
+		"NETWORK":    "10.0.0.0/24",
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want, _ := time.Parse(time.RFC3339, "2024-01-02T15:04:05Z")
+	if !cfg.StartedAt.Equal(want) {
+		t.Fatalf("StartedAt = %v, want %v", cfg.StartedAt, want)
+	}
+	if cfg.Timeout != 30*time.Second {
+		t.Fatalf("Timeout = %v, want 30s", cfg.Timeout)
+	}
+	if cfg.Endpoint.Host != "example.com" || cfg.Endpoint.Path != "/path" {
+		t.Fatalf("Endpoint = %v, want host=example.com path=/path", cfg.Endpoint)
+	}
+	if cfg.Host.String() != "127.0.0.1" {
+		t.Fatalf("Host = %v, want 127.0.0.1", cfg.Host)
+	}
+	if cfg.Network.String() != "10.0.0.0/24" {
+		t.Fatalf("Network = %v, want 10.0.0.0/24", cfg.Network)
+	}
+}
+
+type testSetterValue struct {
+	raw string
+}
+
+func (s *testSetterValue) SetValue(value string) error {
+	s.raw = "set:" + value
+	return nil
+}
+
+type testSetterConfig struct {
+	Value testSetterValue `env:"VALUE"`
+}
+
+func TestSetFieldDataHonorsSetterInterface(t *testing.T) {
+	var cfg testSetterConfig
+	if err := loadFromEnv(t, &cfg, map[string]string{"VALUE": "hello"}); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Value.raw != "set:hello" {
+		t.Fatalf("Value.raw = %q, want %q", cfg.Value.raw, "set:hello")
+	}
+}
+
+func TestSetFieldDataPointer(t *testing.T) {
+	type cfgT struct {
+		Port *int `env:"PTR_PORT"`
+	}
+	var cfg cfgT
+	if err := loadFromEnv(t, &cfg, map[string]string{"PTR_PORT": "9090"}); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Port == nil || *cfg.Port != 9090 {
+		t.Fatalf("Port = %v, want pointer to 9090", cfg.Port)
+	}
+}
@@ -0,0 +1,229 @@
+package aconfig
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const validateTag = "validate"
+
+// Validator can be implemented by the struct passed to Load, or any of
+// its nested structs, to run arbitrary validation once all providers
+// have populated it. It is invoked in addition to, and after, the
+// `validate` struct tag constraints.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError is one failed validation, either from a `validate` struct
+// tag or a Validator.Validate() call.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Err.Error())
+}
+
+// ValidationError aggregates every FieldError found in a single Load
+// call, so callers see all problems at once instead of one at a time.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return "aconfig: validation failed: " + strings.Join(msgs, "; ")
+}
+
+// validate runs `validate` tag constraints over every leaf field, then
+// any Validator.Validate() methods found on dst and its nested structs.
+func (l *Loader) validate(dst interface{}) error {
+	return validateFields(l.fields, dst)
+}
+
+// validateFields runs `validate` tag constraints over fields, then any
+// Validator.Validate() methods found on dst and its nested structs. It
+// is the shared implementation behind Loader.validate and reload, which
+// validates a freshly-loaded struct before swapping reloadable fields
+// into the live one.
+func validateFields(fields []*fieldData, dst interface{}) error {
+	var verr ValidationError
+	for _, fd := range fields {
+		if err := validateField(fd); err != nil {
+			verr.Errors = append(verr.Errors, FieldError{Field: fd.FullName(), Err: err})
+		}
+	}
+	verr.Errors = append(verr.Errors, runValidators(dst)...)
+
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return &verr
+}
+
+// validateField checks every comma-separated constraint in fd's
+// `validate` tag, e.g. `validate:"min=1,max=100"`. Note a `regexp`
+// constraint can't contain a literal comma (as in `{1,3}` quantifiers),
+// even on its own, since the whole tag is split on commas.
+func validateField(fd *fieldData) error {
+	tag := fd.Field.Tag.Get(validateTag)
+	if tag == "" {
+		return nil
+	}
+	for _, constraint := range splitTag(tag) {
+		if err := checkConstraint(fd, constraint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkConstraint(fd *fieldData, constraint string) error {
+	name, arg, _ := strings.Cut(constraint, "=")
+	switch name {
+	case "required":
+		if fd.Value.IsZero() {
+			return fmt.Errorf("is required")
+		}
+	case "min":
+		return checkMin(fd, arg)
+	case "max":
+		return checkMax(fd, arg)
+	case "oneof":
+		return checkOneOf(fd, arg)
+	case "regexp":
+		return checkRegexp(fd, arg)
+	default:
+		return fmt.Errorf("unknown validate constraint %q", name)
+	}
+	return nil
+}
+
+func checkMin(fd *fieldData, arg string) error {
+	limit, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return err
+	}
+	if val, ok := numericValue(fd.Value); ok {
+		if val < limit {
+			return fmt.Errorf("must be >= %s", arg)
+		}
+		return nil
+	}
+	if fd.Value.Kind() == reflect.String && float64(len(fd.Value.String())) < limit {
+		return fmt.Errorf("length must be >= %s", arg)
+	}
+	return nil
+}
+
+func checkMax(fd *fieldData, arg string) error {
+	limit, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return err
+	}
+	if val, ok := numericValue(fd.Value); ok {
+		if val > limit {
+			return fmt.Errorf("must be <= %s", arg)
+		}
+		return nil
+	}
+	if fd.Value.Kind() == reflect.String && float64(len(fd.Value.String())) > limit {
+		return fmt.Errorf("length must be <= %s", arg)
+	}
+	return nil
+}
+
+func checkOneOf(fd *fieldData, arg string) error {
+	allowed := strings.Fields(arg)
+	value := fmt.Sprintf("%v", fd.Value.Interface())
+	for _, a := range allowed {
+		if a == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %v", allowed)
+}
+
+func checkRegexp(fd *fieldData, pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	if !re.MatchString(fd.Value.String()) {
+		return fmt.Errorf("must match %q", pattern)
+	}
+	return nil
+}
+
+func numericValue(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// runValidators walks dst and its nested structs, calling Validate() on
+// every one that implements Validator.
+func runValidators(dst interface{}) []FieldError {
+	var errs []FieldError
+	walkValidators(reflect.ValueOf(dst), "", &errs)
+	return errs
+}
+
+func walkValidators(v reflect.Value, path string, errs *[]FieldError) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	if v.CanAddr() {
+		if validator, ok := v.Addr().Interface().(Validator); ok {
+			if err := validator.Validate(); err != nil {
+				label := path
+				if label == "" {
+					label = v.Type().Name()
+				}
+				*errs = append(*errs, FieldError{Field: label, Err: err})
+			}
+		}
+	}
+
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		fieldValue := v.Field(i)
+		field := t.Field(i)
+
+		underlying := field.Type
+		if underlying.Kind() == reflect.Ptr {
+			underlying = underlying.Elem()
+		}
+		if underlying.Kind() != reflect.Struct || isLeafStruct(underlying) {
+			continue
+		}
+
+		childPath := field.Name
+		if path != "" {
+			childPath = path + "." + field.Name
+		}
+		walkValidators(fieldValue, childPath, errs)
+	}
+}
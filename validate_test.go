@@ -0,0 +1,54 @@
+package aconfig
+
+import (
+	"errors"
+	"testing"
+)
+
+type testValidateConfig struct {
+	Port int    `validate:"min=1,max=65535"`
+	Name string `validate:"oneof=a b c"`
+	Code string `validate:"regexp=^[A-Z]{3}$"`
+}
+
+func TestValidateFieldsConstraints(t *testing.T) {
+	cfg := testValidateConfig{Port: 8080, Name: "b", Code: "ABC"}
+	fields := getFields(&cfg)
+	if err := validateFields(fields, &cfg); err != nil {
+		t.Fatalf("expected valid config to pass, got: %v", err)
+	}
+
+	cfg = testValidateConfig{Port: 0, Name: "z", Code: "abc"}
+	fields = getFields(&cfg)
+	err := validateFields(fields, &cfg)
+	if err == nil {
+		t.Fatal("expected invalid config to fail validation")
+	}
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected *ValidationError, got %T", err)
+	}
+	if len(verr.Errors) != 3 {
+		t.Fatalf("got %d errors, want 3: %v", len(verr.Errors), verr.Errors)
+	}
+}
+
+type testValidatorConfig struct {
+	Port int
+}
+
+func (c *testValidatorConfig) Validate() error {
+	if c.Port == 0 {
+		return errors.New("port must not be zero")
+	}
+	return nil
+}
+
+func TestValidateFieldsRunsValidatorInterface(t *testing.T) {
+	cfg := testValidatorConfig{Port: 0}
+	fields := getFields(&cfg)
+	err := validateFields(fields, &cfg)
+	if err == nil {
+		t.Fatal("expected Validator.Validate() failure to surface")
+	}
+}
@@ -0,0 +1,49 @@
+package aconfig
+
+import (
+	"fmt"
+	"strings"
+
+	vapi "github.com/hashicorp/vault/api"
+)
+
+// VaultProvider reads field values from a single HashiCorp Vault secret.
+// Every field's FullName() is looked up as a key inside the secret stored
+// at Path, e.g. a field Server.Port maps to the key "server.port".
+type VaultProvider struct {
+	// Client is an authenticated Vault client, owned by the caller.
+	Client *vapi.Client
+	// Path is the secret path to read, e.g. "secret/data/myapp".
+	Path string
+}
+
+func (p *VaultProvider) Name() string { return "vault" }
+
+func (p *VaultProvider) Load(_ interface{}, fields []*fieldData) error {
+	secret, err := p.Client.Logical().Read(p.Path)
+	if err != nil {
+		return err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil
+	}
+
+	data := secret.Data
+	// KV v2 nests the actual values under a "data" key.
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
+	for _, field := range fields {
+		key := strings.ToLower(field.FullName())
+		raw, ok := data[key]
+		if !ok {
+			continue
+		}
+		if err := setFieldData(field, fmt.Sprintf("%v", raw)); err != nil {
+			return err
+		}
+		field.Source = "vault:" + p.Path + "#" + key
+	}
+	return nil
+}
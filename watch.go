@@ -0,0 +1,127 @@
+package aconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FieldChange describes one field whose value differed between two
+// reloads. OldValue and NewValue are the field's values before and after
+// the reload, formatted with %v; non-reloadable fields are still reported here even though the live
+// struct isn't mutated for them.
+type FieldChange struct {
+	Name     string
+	OldValue string
+	NewValue string
+}
+
+// Watch re-reads the configuration whenever a file passed in
+// LoaderConfig.Files changes on disk, and at LoaderConfig.WatchInterval
+// otherwise, to also pick up changes from remote providers like etcd,
+// Consul or Vault. Fields tagged `reloadable:"true"` are swapped into
+// the struct originally passed to Load, under Loader.mu; other fields
+// are left untouched but still reported in onChange's diff. Callers
+// reading a reloadable field from another goroutine while Watch is
+// running must wrap that read in Loader.RLock/RUnlock. If the reloaded
+// configuration fails `validate` tag constraints or a Validator, no
+// reloadable field is swapped and the failure is passed to onChange as
+// err alongside the diff that was attempted. Watch blocks until ctx is
+// done or an unrecoverable error occurs.
+func (l *Loader) Watch(ctx context.Context, onChange func(diff []FieldChange, err error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = watcher.Close() }()
+
+	for _, file := range l.config.Files {
+		if err := watcher.Add(file); err != nil {
+			return fmt.Errorf("aconfig: watch %q: %s", file, err.Error())
+		}
+	}
+
+	interval := l.config.WatchInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if err := l.reload(onChange); err != nil {
+				return err
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		case <-ticker.C:
+			if err := l.reload(onChange); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// reload runs every provider into a fresh copy of the original struct,
+// diffs it field by field against the live struct, validates the fresh
+// struct, then - only if it's valid - applies reloadable fields and
+// reports the full diff to onChange. An invalid reload leaves the live
+// struct untouched; its validation error is still passed to onChange
+// alongside the diff that would have been applied.
+func (l *Loader) reload(onChange func(diff []FieldChange, err error)) error {
+	fresh := reflect.New(reflect.TypeOf(l.dst).Elem()).Interface()
+	freshFields := getFields(fresh)
+
+	for _, p := range l.providers() {
+		if err := p.Load(fresh, freshFields); err != nil {
+			return fmt.Errorf("aconfig: %s provider: %s", p.Name(), err.Error())
+		}
+	}
+
+	vErr := validateFields(freshFields, fresh)
+
+	var diff []FieldChange
+
+	l.mu.Lock()
+	for i, fd := range l.fields {
+		newFd := freshFields[i]
+
+		oldStr := fmt.Sprintf("%v", fd.Value.Interface())
+		newStr := fmt.Sprintf("%v", newFd.Value.Interface())
+		if oldStr == newStr {
+			continue
+		}
+
+		diff = append(diff, FieldChange{
+			Name:     fd.FullName(),
+			OldValue: oldStr,
+			NewValue: newStr,
+		})
+
+		if fd.Reloadable && vErr == nil {
+			fd.Value.Set(newFd.Value)
+		}
+	}
+	l.mu.Unlock()
+
+	if (len(diff) > 0 || vErr != nil) && onChange != nil {
+		onChange(diff, vErr)
+	}
+	return nil
+}